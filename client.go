@@ -2,7 +2,9 @@ package ctrader
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -22,17 +24,29 @@ type clientTransport interface {
 }
 
 type Client[T clientTransport] struct {
-	Live         bool
-	ClientID     string
-	Secret       string
-	Transport    T
-	Logger       *slog.Logger
-	HandlerEvent func(proto.Message)
+	Live                   bool
+	ClientID               string
+	Secret                 string
+	Transport              T
+	Logger                 *slog.Logger
+	HandlerEvent           func(proto.Message)
+	HandlerReconnect       func(ReconnectEvent)
+	ReconnectPolicy        ReconnectPolicy
+	Throttler              Throttler
+	SubscriptionBufferSize int
 
-	stopSignal           atomic.Bool
-	wg                   sync.WaitGroup
-	requestRegistry      map[string]chan *openapi.ProtoMessage
-	requestRegistryMutex sync.Mutex
+	stopSignal            atomic.Bool
+	wg                    sync.WaitGroup
+	requestRegistry       map[string]chan *responseEnvelope
+	requestRegistryMutex  sync.Mutex
+	session               sessionState
+	payloadFactories      map[uint32]func() proto.Message
+	requestPayloadTypes   map[reflect.Type]uint32
+	payloadFactoriesMutex sync.Mutex
+	throttlerOnce         sync.Once
+	subs                  subscriptionEngine
+	symbolCache           atomic.Pointer[SymbolCache[T]]
+	symbolCacheOnce       sync.Once
 }
 
 func (c *Client[T]) Start() error {
@@ -42,11 +56,12 @@ func (c *Client[T]) Start() error {
 	} else {
 		address = "demo.ctraderapi.com:5035"
 	}
+	c.stopSignal.Store(false)
 	c.Transport.setHandler(c.handlerMessage, c.handlerError)
 	if err := c.Transport.start(address); err != nil {
-		return fmt.Errorf("failed to open the transport: %w", err)
+		return fmt.Errorf("failed to open the transport: %w: %w", ErrTransportClosed, err)
 	}
-	c.requestRegistry = make(map[string]chan *openapi.ProtoMessage)
+	c.requestRegistry = make(map[string]chan *responseEnvelope)
 	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Second)
 	defer ctxCancel()
 	if err := c.applicationAuthorization(ctx); err != nil {
@@ -56,11 +71,23 @@ func (c *Client[T]) Start() error {
 	return nil
 }
 
+// replay resumes the account authorizations and subscriptions recorded in the session state. It
+// is called after a reconnect, once the application has re-authenticated, so the server sees the
+// same session it had before the connection dropped.
+func (c *Client[T]) replay(ctx context.Context) error {
+	for _, req := range c.session.snapshot() {
+		if _, err := c.send(ctx, req, true); err != nil {
+			return fmt.Errorf("failed to replay %T: %w", req, err)
+		}
+	}
+	return nil
+}
+
 func (c *Client[T]) Stop() error {
 	c.stopSignal.Store(true)
 	c.wg.Wait()
 	if err := c.Transport.stop(); err != nil {
-		return fmt.Errorf("failed to close the transport: %w", err)
+		return fmt.Errorf("failed to close the transport: %w: %w", ErrTransportClosed, err)
 	}
 	return nil
 }
@@ -81,7 +108,17 @@ func (c *Client[T]) handlerMessage(payload []byte) {
 			c.Logger.Error("failed to unmarshal payload", "error", err)
 			return
 		}
-		c.HandlerEvent(message)
+		// PROTO_OA_TRAILING_SL_CHANGED_EVENT carries no symbol metadata to invalidate and is left
+		// to fall through to HandlerEvent like any other event the subscription engine doesn't
+		// route.
+		if changed, ok := message.(*openapi.ProtoOASymbolChangedEvent); ok {
+			if cache := c.symbolCache.Load(); cache != nil {
+				cache.Invalidate(changed.GetCtidTraderAccountId(), changed.GetSymbolId())
+			}
+		}
+		if !c.subs.route(message) {
+			c.HandlerEvent(message)
+		}
 	} else {
 		c.requestRegistryMutex.Lock()
 		chanResponse, ok := c.requestRegistry[*msg.ClientMsgId]
@@ -90,36 +127,90 @@ func (c *Client[T]) handlerMessage(payload []byte) {
 			c.Logger.Error("client message ID not found", "clientMessageID", *msg.ClientMsgId)
 			return
 		}
-		chanResponse <- &msg
+		chanResponse <- &responseEnvelope{message: &msg}
 	}
 }
 
+// handlerError is invoked by the transport when the underlying connection is lost. It cancels
+// every in-flight request with ErrReconnecting, then reconnects following c.ReconnectPolicy,
+// replaying every account authorization and subscription recorded in the session state before
+// handing control back to the caller.
 func (c *Client[T]) handlerError(err error) {
-	for {
-		if err := c.Stop(); err != nil {
-			c.Logger.Error("failed to stop the client", "error", err.Error())
-			time.Sleep(time.Second)
-			continue
+	c.Logger.Error("transport reported an error, reconnecting", "error", err)
+	c.abortPendingRequests(ErrReconnecting)
+	c.emitReconnectEvent(ReconnectEvent{Type: ReconnectStart, Err: err})
+
+	policy := c.ReconnectPolicy
+	if policy == (ReconnectPolicy{}) {
+		policy = DefaultReconnectPolicy()
+	}
+
+	lastErr := err
+	for attempt := 0; policy.MaxAttempts == 0 || attempt < policy.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(policy.delay(attempt))
 		}
-		if err := c.Start(); err != nil {
-			c.Logger.Error("failed to start the client", "error", err.Error())
-			time.Sleep(time.Second)
+		if err := c.reconnect(); err != nil {
+			c.Logger.Error("failed to reconnect", "attempt", attempt, "error", err)
+			lastErr = err
 			continue
 		}
-		break
+		c.emitReconnectEvent(ReconnectEvent{Type: ReconnectSuccess})
+		return
+	}
+	c.emitReconnectEvent(ReconnectEvent{Type: ReconnectFailed, Err: lastErr})
+}
+
+// reconnect stops the transport, starts it again and replays the session state. It leaves
+// c.session untouched on success so a subsequent reconnect can replay the same state again.
+func (c *Client[T]) reconnect() error {
+	if err := c.Stop(); err != nil {
+		return fmt.Errorf("failed to stop the client: %w", err)
+	}
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("failed to start the client: %w", err)
+	}
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer ctxCancel()
+	if err := c.replay(ctx); err != nil {
+		return fmt.Errorf("failed to replay the session state: %w", err)
+	}
+	return nil
+}
+
+// abortPendingRequests releases every caller currently blocked in send, handing each one err
+// instead of leaking its response channel.
+func (c *Client[T]) abortPendingRequests(err error) {
+	c.requestRegistryMutex.Lock()
+	registry := c.requestRegistry
+	c.requestRegistry = make(map[string]chan *responseEnvelope)
+	c.requestRegistryMutex.Unlock()
+
+	for _, chanResponse := range registry {
+		chanResponse <- &responseEnvelope{err: err}
+	}
+}
+
+func (c *Client[T]) emitReconnectEvent(event ReconnectEvent) {
+	if c.HandlerReconnect != nil {
+		c.HandlerReconnect(event)
 	}
 }
 
 func (c *Client[T]) send(
-	ctx context.Context, req proto.Message, reqTypeRaw int32, hasResponse bool,
+	ctx context.Context, req proto.Message, hasResponse bool,
 ) (proto.Message, error) {
+	reqType, err := c.payloadTypeOf(req)
+	if err != nil {
+		return nil, err
+	}
+
 	payloadBase, err := proto.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal base request: %w", err)
 	}
 
 	id := uuid.NewV4().String()
-	reqType := uint32(reqTypeRaw)
 	message := openapi.ProtoMessage{
 		ClientMsgId: &id,
 		Payload:     payloadBase,
@@ -130,17 +221,27 @@ func (c *Client[T]) send(
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	var chanResponse chan *openapi.ProtoMessage
+	var chanResponse chan *responseEnvelope
 	if hasResponse {
-		chanResponse = make(chan *openapi.ProtoMessage, 1)
+		chanResponse = make(chan *responseEnvelope, 1)
 		c.requestRegistryMutex.Lock()
 		c.requestRegistry[id] = chanResponse
 		c.requestRegistryMutex.Unlock()
-		defer delete(c.requestRegistry, id)
+		defer func() {
+			c.requestRegistryMutex.Lock()
+			delete(c.requestRegistry, id)
+			c.requestRegistryMutex.Unlock()
+		}()
+	}
+
+	class, accountID := classOf(req), accountOf(req)
+	if err := c.throttler().Wait(ctx, class, accountID); err != nil {
+		return nil, err
 	}
 
+	c.session.record(req)
 	if err := c.Transport.send(payload); err != nil {
-		return nil, fmt.Errorf("failed to send the message: %w", err)
+		return nil, fmt.Errorf("failed to send the message: %w: %w", ErrTransportClosed, err)
 	}
 
 	if !hasResponse {
@@ -149,8 +250,15 @@ func (c *Client[T]) send(
 
 	select {
 	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %w", ErrTimeout, ctx.Err())
+		}
 		return nil, fmt.Errorf("context error: %w", ctx.Err())
-	case messageBase := <-chanResponse:
+	case envelope := <-chanResponse:
+		if envelope.err != nil {
+			return nil, envelope.err
+		}
+		messageBase := envelope.message
 		message, err := c.responseMapping(*messageBase.PayloadType)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get the response type: %w", err)
@@ -158,111 +266,12 @@ func (c *Client[T]) send(
 		if err := proto.Unmarshal(messageBase.Payload, message); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal the response: %w", err)
 		}
+		if apiErr, ok := errorFromPayload(message); ok {
+			if errors.Is(apiErr, ErrThrottled) {
+				c.throttler().OnThrottled(class, accountID, apiErr.RetriableAfter)
+			}
+			return nil, apiErr
+		}
 		return message, nil
 	}
-}
-
-func (c *Client[T]) responseMapping(payloadType uint32) (proto.Message, error) {
-	var response proto.Message
-	switch payloadType {
-	case uint32(openapi.ProtoPayloadType_PROTO_MESSAGE):
-		response = &openapi.ProtoMessage{}
-	case uint32(openapi.ProtoPayloadType_ERROR_RES):
-		response = &openapi.ProtoErrorRes{}
-	case uint32(openapi.ProtoPayloadType_HEARTBEAT_EVENT):
-		response = &openapi.ProtoHeartbeatEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_APPLICATION_AUTH_RES):
-		response = &openapi.ProtoOAApplicationAuthRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_ACCOUNT_AUTH_RES):
-		response = &openapi.ProtoOAAccountAuthRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_VERSION_RES):
-		response = &openapi.ProtoOAVersionRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_TRAILING_SL_CHANGED_EVENT):
-		response = &openapi.ProtoOATrailingSLChangedEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_ASSET_LIST_RES):
-		response = &openapi.ProtoOAAssetListRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_SYMBOLS_LIST_RES):
-		response = &openapi.ProtoOASymbolsListRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_SYMBOL_BY_ID_RES):
-		response = &openapi.ProtoOASymbolByIdRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_SYMBOLS_FOR_CONVERSION_RES):
-		response = &openapi.ProtoOASymbolsForConversionRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_SYMBOL_CHANGED_EVENT):
-		response = &openapi.ProtoOASymbolChangedEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_TRADER_RES):
-		response = &openapi.ProtoOATraderRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_TRADER_UPDATE_EVENT):
-		response = &openapi.ProtoOAMarginCallUpdateEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_RECONCILE_RES):
-		response = &openapi.ProtoOAReconcileRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_EXECUTION_EVENT):
-		response = &openapi.ProtoOAExecutionEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_SUBSCRIBE_SPOTS_RES):
-		response = &openapi.ProtoOASubscribeSpotsRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_UNSUBSCRIBE_SPOTS_RES):
-		response = &openapi.ProtoOAUnsubscribeSpotsRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_SPOT_EVENT):
-		response = &openapi.ProtoOASpotEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_ORDER_ERROR_EVENT):
-		response = &openapi.ProtoOAOrderErrorEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_DEAL_LIST_RES):
-		response = &openapi.ProtoOADealListRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_GET_TRENDBARS_RES):
-		response = &openapi.ProtoOAGetTrendbarsRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_EXPECTED_MARGIN_RES):
-		response = &openapi.ProtoOAExpectedMarginRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_MARGIN_CHANGED_EVENT):
-		response = &openapi.ProtoOAMarginChangedEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_ERROR_RES):
-		response = &openapi.ProtoOAErrorRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_CASH_FLOW_HISTORY_LIST_RES):
-		response = &openapi.ProtoOACashFlowHistoryListRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_GET_TICKDATA_RES):
-		response = &openapi.ProtoOAGetTickDataRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_ACCOUNTS_TOKEN_INVALIDATED_EVENT):
-		response = &openapi.ProtoOAAccountsTokenInvalidatedEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_CLIENT_DISCONNECT_EVENT):
-		response = &openapi.ProtoOAClientDisconnectEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_GET_ACCOUNTS_BY_ACCESS_TOKEN_RES):
-		response = &openapi.ProtoOAGetAccountListByAccessTokenRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_GET_CTID_PROFILE_BY_TOKEN_RES):
-		response = &openapi.ProtoOAGetCtidProfileByTokenRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_ASSET_CLASS_LIST_RES):
-		response = &openapi.ProtoOAAssetClassListRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_DEPTH_EVENT):
-		response = &openapi.ProtoOADepthEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_SUBSCRIBE_DEPTH_QUOTES_RES):
-		response = &openapi.ProtoOASubscribeDepthQuotesRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_UNSUBSCRIBE_DEPTH_QUOTES_RES):
-		response = &openapi.ProtoOAUnsubscribeDepthQuotesRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_SYMBOL_CATEGORY_RES):
-		response = &openapi.ProtoOASymbolCategoryListRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_ACCOUNT_LOGOUT_RES):
-		response = &openapi.ProtoOAAccountLogoutRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_ACCOUNT_DISCONNECT_EVENT):
-		response = &openapi.ProtoOAAccountDisconnectEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_SUBSCRIBE_LIVE_TRENDBAR_RES):
-		response = &openapi.ProtoOASubscribeLiveTrendbarRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_UNSUBSCRIBE_LIVE_TRENDBAR_RES):
-		response = &openapi.ProtoOAUnsubscribeLiveTrendbarRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_MARGIN_CALL_LIST_RES):
-		response = &openapi.ProtoOAMarginCallListRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_MARGIN_CALL_UPDATE_RES):
-		response = &openapi.ProtoOAMarginCallUpdateRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_MARGIN_CALL_UPDATE_EVENT):
-		response = &openapi.ProtoOAMarginCallUpdateEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_MARGIN_CALL_TRIGGER_EVENT):
-		response = &openapi.ProtoOAMarginCallTriggerEvent{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_REFRESH_TOKEN_RES):
-		response = &openapi.ProtoOARefreshTokenRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_ORDER_LIST_RES):
-		response = &openapi.ProtoOAOrderListRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_GET_DYNAMIC_LEVERAGE_RES):
-		response = &openapi.ProtoOAGetDynamicLeverageByIDRes{}
-	case uint32(openapi.ProtoOAPayloadType_PROTO_OA_DEAL_LIST_BY_POSITION_ID_RES):
-		response = &openapi.ProtoOADealListByPositionIdRes{}
-	default:
-		return nil, fmt.Errorf("unknow message type '%d'", payloadType)
-	}
-	return response, nil
 }
\ No newline at end of file
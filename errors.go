@@ -0,0 +1,101 @@
+package ctrader
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diegobernardes/ctrader/openapi"
+)
+
+// Error wraps a PROTO_OA_ERROR_RES or ERROR_RES payload returned by the server. It implements
+// Unwrap and Is against the sentinel values declared below, so callers can use errors.Is instead
+// of comparing Code by hand.
+type Error struct {
+	// Code is the raw errorCode string documented by the cTrader Open API, e.g.
+	// "ACCOUNT_NOT_AUTHORIZED" or "REQUEST_FREQUENCY_EXCEEDED".
+	Code string
+	// Description is the human-readable description attached to the response, if any.
+	Description string
+	// MaintenanceEndTimestamp is set when Code is CH_CTID_TRADER_ACCOUNT_MAINTENANCE_ERROR, and
+	// reports when the server expects maintenance to finish.
+	MaintenanceEndTimestamp *int64
+	// RetriableAfter is how long the caller should wait before retrying, derived from
+	// Description when the server embeds a cool-off period (e.g. REQUEST_FREQUENCY_EXCEEDED).
+	// It is set only for codes that map to ErrThrottled, and is five seconds when their
+	// Description carries no such hint; it is zero for every other code.
+	RetriableAfter time.Duration
+
+	sentinel error
+}
+
+func (e *Error) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("ctrader: %s: %s", e.Code, e.Description)
+	}
+	return fmt.Sprintf("ctrader: %s", e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	return e.sentinel
+}
+
+// Is reports whether target is one of the sentinel errors this Error maps to, so
+// errors.Is(err, ctrader.ErrThrottled) works without the caller inspecting Code.
+func (e *Error) Is(target error) bool {
+	return e.sentinel != nil && errors.Is(e.sentinel, target)
+}
+
+// Sentinel errors for the errorCode values documented by the cTrader Open API. Not every code
+// has a sentinel; codes without one still produce an *Error, but errors.Is matches it against
+// none of these sentinels, so callers compare its Code field instead.
+var (
+	ErrNotAuthenticated  = errors.New("ctrader: not authenticated")
+	ErrTimeout           = errors.New("ctrader: timeout")
+	ErrThrottled         = errors.New("ctrader: throttled")
+	ErrSymbolNotFound    = errors.New("ctrader: symbol not found")
+	ErrTransportClosed   = errors.New("ctrader: transport closed")
+	ErrPayloadNotAllowed = errors.New("ctrader: payload type not allowed for this client")
+)
+
+// errorCodeSentinel maps documented errorCode strings to the sentinel error send should wrap
+// them with. Codes not present here still produce a usable *Error, just without a sentinel.
+var errorCodeSentinel = map[string]error{
+	"CH_CLIENT_AUTH_FAILURE":               ErrNotAuthenticated,
+	"CH_CLIENT_NOT_AUTHENTICATED":          ErrNotAuthenticated,
+	"ACCOUNT_NOT_AUTHORIZED":               ErrNotAuthenticated,
+	"OA_AUTH_TOKEN_EXPIRED":                ErrNotAuthenticated,
+	"BLOCKED_PAYLOAD_TYPE":                 ErrPayloadNotAllowed,
+	"REQUEST_FREQUENCY_EXCEEDED":           ErrThrottled,
+	"CH_CLIENT_REQUEST_FREQUENCY_TOO_HIGH": ErrThrottled,
+	"SYMBOL_NOT_FOUND":                     ErrSymbolNotFound,
+}
+
+// newError converts a PROTO_OA_ERROR_RES or ERROR_RES payload into an *Error, attaching the
+// sentinel registered for its Code, if any.
+func newError(code, description string, maintenanceEndTimestamp *int64) *Error {
+	sentinel := errorCodeSentinel[code]
+	var retriableAfter time.Duration
+	if sentinel == ErrThrottled {
+		retriableAfter = parseRetriableAfter(description)
+	}
+	return &Error{
+		Code:                    code,
+		Description:             description,
+		MaintenanceEndTimestamp: maintenanceEndTimestamp,
+		RetriableAfter:          retriableAfter,
+		sentinel:                sentinel,
+	}
+}
+
+// errorFromPayload builds an *Error from whichever error payload type the server sent.
+func errorFromPayload(message interface{}) (*Error, bool) {
+	switch m := message.(type) {
+	case *openapi.ProtoOAErrorRes:
+		return newError(m.GetErrorCode(), m.GetDescription(), m.MaintenanceEndTimestamp), true
+	case *openapi.ProtoErrorRes:
+		return newError(m.GetErrorCode(), m.GetDescription(), nil), true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,68 @@
+package ctrader
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/diegobernardes/ctrader/openapi"
+)
+
+func TestErrorFromPayloadSentinels(t *testing.T) {
+	tests := []struct {
+		name    string
+		message interface{}
+		want    error
+	}{
+		{
+			name:    "account not authorized maps to ErrNotAuthenticated",
+			message: &openapi.ProtoOAErrorRes{ErrorCode: strPtr("ACCOUNT_NOT_AUTHORIZED")},
+			want:    ErrNotAuthenticated,
+		},
+		{
+			name:    "blocked payload type maps to ErrPayloadNotAllowed",
+			message: &openapi.ProtoOAErrorRes{ErrorCode: strPtr("BLOCKED_PAYLOAD_TYPE")},
+			want:    ErrPayloadNotAllowed,
+		},
+		{
+			name:    "request frequency exceeded maps to ErrThrottled",
+			message: &openapi.ProtoOAErrorRes{ErrorCode: strPtr("REQUEST_FREQUENCY_EXCEEDED")},
+			want:    ErrThrottled,
+		},
+		{
+			name:    "symbol not found maps to ErrSymbolNotFound",
+			message: &openapi.ProtoErrorRes{ErrorCode: strPtr("SYMBOL_NOT_FOUND")},
+			want:    ErrSymbolNotFound,
+		},
+		{
+			name:    "unknown code has no sentinel",
+			message: &openapi.ProtoOAErrorRes{ErrorCode: strPtr("SOME_NEW_CODE")},
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr, ok := errorFromPayload(tt.message)
+			if !ok {
+				t.Fatalf("errorFromPayload() did not recognize %T", tt.message)
+			}
+			if tt.want == nil {
+				if apiErr.Is(ErrNotAuthenticated) || apiErr.Is(ErrThrottled) || apiErr.Is(ErrPayloadNotAllowed) || apiErr.Is(ErrSymbolNotFound) {
+					t.Fatalf("expected no sentinel match, got %#v", apiErr)
+				}
+				return
+			}
+			if !errors.Is(apiErr, tt.want) {
+				t.Fatalf("errors.Is(%#v, %v) = false, want true", apiErr, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorFromPayloadUnrecognizedType(t *testing.T) {
+	if _, ok := errorFromPayload(&openapi.ProtoOASymbolByIdRes{}); ok {
+		t.Fatalf("expected errorFromPayload to reject a non-error payload")
+	}
+}
+
+func strPtr(s string) *string { return &s }
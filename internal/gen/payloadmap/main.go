@@ -0,0 +1,242 @@
+// Command payloadmap regenerates payload_mapping_generated.go by walking the openapi package's
+// generated .pb.go files: it collects every ProtoPayloadType/ProtoOAPayloadType enum constant and
+// every Proto* message struct, matches each constant to the message it names, and emits a
+// register() call for the pair. Running it after regenerating openapi from a newer .proto keeps
+// the table from drifting out of sync, instead of someone hand-editing entries that no longer
+// exist in the enum.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// enumConst is one ProtoPayloadType/ProtoOAPayloadType constant, e.g. PROTO_OA_SYMBOL_BY_ID_REQ.
+type enumConst struct {
+	EnumType string // "ProtoPayloadType" or "ProtoOAPayloadType"
+	Name     string // full constant identifier, e.g. ProtoOAPayloadType_PROTO_OA_SYMBOL_BY_ID_REQ
+	Suffix   string // the part after the enum type's own prefix, e.g. PROTO_OA_SYMBOL_BY_ID_REQ
+}
+
+// overrides maps an enum constant's Suffix to the openapi message type it registers, for the
+// handful of cases messageNameFor can't derive mechanically (irregular abbreviations, or an enum
+// value that replies with a different message than its name implies).
+var overrides = map[string]string{
+	"ERROR_RES":                                       "ProtoErrorRes",
+	"HEARTBEAT_EVENT":                                 "ProtoHeartbeatEvent",
+	"PROTO_OA_GET_TICKDATA_REQ":                       "ProtoOAGetTickDataReq",
+	"PROTO_OA_GET_TICKDATA_RES":                       "ProtoOAGetTickDataRes",
+	"PROTO_OA_TRADER_UPDATE_EVENT":                    "ProtoOAMarginCallUpdateEvent",
+	"PROTO_OA_SYMBOL_CATEGORY_REQ":                    "ProtoOASymbolCategoryListReq",
+	"PROTO_OA_SYMBOL_CATEGORY_RES":                    "ProtoOASymbolCategoryListRes",
+	"PROTO_OA_GET_ACCOUNTS_BY_ACCESS_TOKEN_REQ":       "ProtoOAGetAccountListByAccessTokenReq",
+	"PROTO_OA_GET_ACCOUNTS_BY_ACCESS_TOKEN_RES":       "ProtoOAGetAccountListByAccessTokenRes",
+	"PROTO_OA_GET_DYNAMIC_LEVERAGE_REQ":               "ProtoOAGetDynamicLeverageByIDReq",
+	"PROTO_OA_GET_DYNAMIC_LEVERAGE_RES":               "ProtoOAGetDynamicLeverageByIDRes",
+	// Order management requests reply with PROTO_OA_EXECUTION_EVENT, not a dedicated *_RES message;
+	// there is no *_RES enum value for them at all, so nothing to override here, only *_REQ exists.
+}
+
+// Note: cTrader has no PROTO_OA_NEW_ORDER_RES/AMEND_ORDER_RES/CANCEL_ORDER_RES/
+// CLOSE_POSITION_RES/AMEND_POSITION_SLTP_RES enum values — order management requests reply with
+// the shared PROTO_OA_EXECUTION_EVENT instead. scan() only emits a registration for enum values
+// that actually exist in openapi, so those five never show up here; their *_REQ counterparts
+// still register normally.
+
+type registration struct {
+	EnumExpr string // e.g. openapi.ProtoOAPayloadType_PROTO_OA_SYMBOL_BY_ID_REQ
+	Message  string // e.g. ProtoOASymbolByIdReq
+}
+
+func main() {
+	openapiDir := flag.String("openapi", "./openapi", "path to the openapi package to scan")
+	out := flag.String("out", "payload_mapping_generated.go", "output file path")
+	flag.Parse()
+
+	consts, messages, err := scan(*openapiDir)
+	if err != nil {
+		log.Fatalf("payloadmap: %v", err)
+	}
+
+	var regs []registration
+	for _, c := range consts {
+		msg := overrides[c.Suffix]
+		if msg == "" {
+			msg = messageNameFor(c.Suffix)
+		}
+		if !messages[msg] {
+			log.Printf("payloadmap: skipping %s: no message type %s in openapi", c.Name, msg)
+			continue
+		}
+		regs = append(regs, registration{EnumExpr: c.Name, Message: msg})
+	}
+
+	if err := render(*out, regs); err != nil {
+		log.Fatalf("payloadmap: %v", err)
+	}
+}
+
+// scan parses every .pb.go file under dir and returns the ProtoPayloadType/ProtoOAPayloadType
+// enum constants, in declaration order, plus the set of exported Proto*-prefixed struct type
+// names.
+func scan(dir string) ([]enumConst, map[string]bool, error) {
+	fset := token.NewFileSet()
+	var consts []enumConst
+	messages := map[string]bool{}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".pb.go") {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			switch genDecl.Tok {
+			case token.CONST:
+				consts = append(consts, constsFrom(genDecl)...)
+			case token.TYPE:
+				for _, spec := range genDecl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					if _, ok := typeSpec.Type.(*ast.StructType); !ok {
+						continue
+					}
+					if strings.HasPrefix(typeSpec.Name.Name, "Proto") {
+						messages[typeSpec.Name.Name] = true
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return consts, messages, nil
+}
+
+func constsFrom(decl *ast.GenDecl) []enumConst {
+	var out []enumConst
+	for _, spec := range decl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok || valueSpec.Type == nil {
+			continue
+		}
+		ident, ok := valueSpec.Type.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		enumType := ident.Name
+		if enumType != "ProtoPayloadType" && enumType != "ProtoOAPayloadType" {
+			continue
+		}
+		for _, name := range valueSpec.Names {
+			prefix := enumType + "_"
+			suffix := strings.TrimPrefix(name.Name, prefix)
+			out = append(out, enumConst{
+				EnumType: enumType,
+				Name:     enumType + "_" + suffix,
+				Suffix:   suffix,
+			})
+		}
+	}
+	return out
+}
+
+// messageNameFor derives the openapi message type name an enum constant suffix refers to, e.g.
+// PROTO_OA_SYMBOL_BY_ID_REQ -> ProtoOASymbolByIdReq. It title-cases each underscore-separated
+// word, except that OA, SL and SLTP stay upper case and ID becomes Id, as openapi's generated
+// names render them.
+func messageNameFor(suffix string) string {
+	words := strings.Split(suffix, "_")
+	var b strings.Builder
+	for _, w := range words {
+		switch w {
+		case "ID":
+			b.WriteString("Id")
+		case "OA":
+			b.WriteString("OA")
+		case "SL":
+			b.WriteString("SL")
+		case "SLTP":
+			b.WriteString("SLTP")
+		default:
+			b.WriteString(strings.ToUpper(w[:1]))
+			if len(w) > 1 {
+				b.WriteString(strings.ToLower(w[1:]))
+			}
+		}
+	}
+	return b.String()
+}
+
+const tmplSource = `// Code generated by go generate; DO NOT EDIT.
+//
+//go:generate go run ./internal/gen/payloadmap -out payload_mapping_generated.go ./openapi
+
+package ctrader
+
+import (
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/diegobernardes/ctrader/openapi"
+)
+
+// defaultResponseFactories maps every ProtoPayloadType/ProtoOAPayloadType value to a factory for
+// its message, built by walking the openapi package's generated Go files and matching each
+// *Req/*Res/*Event message to its enum value. Client.RegisterPayloadType overlays entries on top
+// of this table on a per-Client basis, so forks and broker extensions never need to edit this
+// file.
+var defaultResponseFactories = map[uint32]func() proto.Message{}
+
+// defaultRequestPayloadTypes is the inverse of defaultResponseFactories for outbound message
+// types, letting Client.send infer the wire payload type straight from the proto.Message a
+// caller passes in instead of requiring a magic int32 alongside it.
+var defaultRequestPayloadTypes = map[reflect.Type]uint32{}
+
+func init() {
+	register := func(payloadType uint32, factory func() proto.Message) {
+		defaultResponseFactories[payloadType] = factory
+		defaultRequestPayloadTypes[reflect.TypeOf(factory())] = payloadType
+	}
+{{range .}}
+	register(uint32(openapi.{{.EnumExpr}}), func() proto.Message { return &openapi.{{.Message}}{} })
+{{- end}}
+}
+`
+
+// render writes the generated file. regs is already in the order scan() discovered the
+// corresponding enum constants, so the output's register() calls stay stable across reruns.
+func render(path string, regs []registration) error {
+	tmpl, err := template.New("payloadmap").Parse(tmplSource)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return tmpl.Execute(f, regs)
+}
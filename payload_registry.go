@@ -0,0 +1,57 @@
+package ctrader
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// RegisterPayloadType teaches this Client about a payload type the built-in table in
+// payload_mapping_generated.go does not know, e.g. a broker-specific extension of the cTrader
+// Open API. factory must return a fresh, empty instance of the message every time it is called.
+// The registration is also used in the opposite direction: when a caller later passes a message
+// of that same Go type to an outbound request, Client.send uses payloadType for it automatically.
+func (c *Client[T]) RegisterPayloadType(payloadType uint32, factory func() proto.Message) {
+	c.payloadFactoriesMutex.Lock()
+	defer c.payloadFactoriesMutex.Unlock()
+	if c.payloadFactories == nil {
+		c.payloadFactories = make(map[uint32]func() proto.Message)
+		c.requestPayloadTypes = make(map[reflect.Type]uint32)
+	}
+	c.payloadFactories[payloadType] = factory
+	c.requestPayloadTypes[reflect.TypeOf(factory())] = payloadType
+}
+
+// responseMapping returns a fresh, empty message for payloadType, checking overrides registered
+// through RegisterPayloadType before falling back to the generated table.
+func (c *Client[T]) responseMapping(payloadType uint32) (proto.Message, error) {
+	c.payloadFactoriesMutex.Lock()
+	factory, ok := c.payloadFactories[payloadType]
+	c.payloadFactoriesMutex.Unlock()
+	if !ok {
+		factory, ok = defaultResponseFactories[payloadType]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknow message type '%d'", payloadType)
+	}
+	return factory(), nil
+}
+
+// payloadTypeOf infers the wire payload type for an outbound request from its Go type, checking
+// overrides registered through RegisterPayloadType before falling back to the generated table.
+func (c *Client[T]) payloadTypeOf(req proto.Message) (uint32, error) {
+	t := reflect.TypeOf(req)
+
+	c.payloadFactoriesMutex.Lock()
+	payloadType, ok := c.requestPayloadTypes[t]
+	c.payloadFactoriesMutex.Unlock()
+	if !ok {
+		payloadType, ok = defaultRequestPayloadTypes[t]
+	}
+	if !ok {
+		return 0, fmt.Errorf("unknow request type '%T'", req)
+	}
+	return payloadType, nil
+}
@@ -0,0 +1,202 @@
+package ctrader
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/diegobernardes/ctrader/openapi"
+)
+
+// ErrReconnecting is returned by any in-flight send call that was cancelled because the
+// transport dropped and the client is reconnecting. Callers can retry once the reconnect
+// finishes, which HandlerReconnect reports with ReconnectSuccess. send does not wait for a
+// reconnect in progress to finish.
+var ErrReconnecting = errors.New("ctrader: client is reconnecting")
+
+// ReconnectPolicy controls how Client reconnects after the transport reports an error. Delays
+// follow a full-jitter exponential backoff: delay = rand(0, min(MaxDelay, InitialDelay*2^attempt)).
+type ReconnectPolicy struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	// MaxAttempts caps the number of reconnect attempts. Zero means retry forever.
+	MaxAttempts int
+}
+
+// DefaultReconnectPolicy returns the backoff used when Client.ReconnectPolicy is left at its
+// zero value: a one second initial delay, capped at one minute, retried indefinitely.
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		InitialDelay: time.Second,
+		MaxDelay:     time.Minute,
+		MaxAttempts:  0,
+	}
+}
+
+// delay computes the full-jitter backoff for the given attempt, where attempt 0 is the first
+// retry after the initial failure.
+func (p ReconnectPolicy) delay(attempt int) time.Duration {
+	maxDelay := p.MaxDelay
+	backoff := p.InitialDelay << attempt
+	if backoff <= 0 || backoff > maxDelay {
+		backoff = maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// ReconnectEventType identifies the stage of a reconnect reported through
+// Client.HandlerReconnect.
+type ReconnectEventType int
+
+const (
+	// ReconnectStart fires once, right after the transport reports an error and before the
+	// first reconnect attempt.
+	ReconnectStart ReconnectEventType = iota
+	// ReconnectSuccess fires once the connection, application authorization and session state
+	// have all been restored.
+	ReconnectSuccess
+	// ReconnectFailed fires when ReconnectPolicy.MaxAttempts is exhausted without success.
+	ReconnectFailed
+)
+
+// ReconnectEvent is passed to Client.HandlerReconnect to describe a reconnect milestone.
+type ReconnectEvent struct {
+	Type ReconnectEventType
+	// Err is the error that triggered the reconnect (ReconnectStart) or the last attempt's
+	// failure (ReconnectFailed). It is nil for ReconnectSuccess.
+	Err error
+}
+
+// responseEnvelope is what handlerMessage delivers to a pending send call. err is set instead of
+// message when the request was cancelled, e.g. because the client is reconnecting.
+type responseEnvelope struct {
+	message *openapi.ProtoMessage
+	err     error
+}
+
+// subscriptionKey identifies a single subscription so repeated Subscribe calls for the same
+// account/symbol/period/kind are deduplicated and replayed at most once after a reconnect.
+type subscriptionKey struct {
+	ctidTraderAccountId int64
+	symbolId            int64
+	period              int32
+	kind                subscriptionKind
+}
+
+// sessionState tracks everything that must be restored after a reconnect: every account
+// authorization performed and every active subscription, keyed so that replay sends each one
+// exactly once, in the order it was first requested. The *Order slices are the source of truth
+// for ordering, since Go map iteration is randomized.
+type sessionState struct {
+	mu                sync.Mutex
+	accountAuths      map[int64]proto.Message
+	accountAuthOrder  []int64
+	subscriptions     map[subscriptionKey]proto.Message
+	subscriptionOrder []subscriptionKey
+}
+
+// record inspects req and, if it is an account authorization or a subscribe request this client
+// understands, stores it so it can be replayed after a reconnect. Everything else is ignored.
+func (s *sessionState) record(req proto.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.accountAuths == nil {
+		s.accountAuths = make(map[int64]proto.Message)
+		s.subscriptions = make(map[subscriptionKey]proto.Message)
+	}
+
+	switch r := req.(type) {
+	case *openapi.ProtoOAAccountAuthReq:
+		s.setAccountAuth(r.GetCtidTraderAccountId(), req)
+	case *openapi.ProtoOASubscribeSpotsReq:
+		accountID := r.GetCtidTraderAccountId()
+		for _, symbolID := range r.GetSymbolId() {
+			key := subscriptionKey{ctidTraderAccountId: accountID, symbolId: symbolID, kind: kindSpot}
+			// Store a single-symbol copy keyed per symbol, not the original multi-symbol req:
+			// snapshot() emits one entry per key, and replaying the same N-symbol req N times
+			// would re-subscribe every symbol N times on every reconnect.
+			s.setSubscription(key, &openapi.ProtoOASubscribeSpotsReq{
+				CtidTraderAccountId: &accountID,
+				SymbolId:            []int64{symbolID},
+			})
+		}
+	case *openapi.ProtoOASubscribeDepthQuotesReq:
+		accountID := r.GetCtidTraderAccountId()
+		for _, symbolID := range r.GetSymbolId() {
+			key := subscriptionKey{ctidTraderAccountId: accountID, symbolId: symbolID, kind: kindDepth}
+			s.setSubscription(key, &openapi.ProtoOASubscribeDepthQuotesReq{
+				CtidTraderAccountId: &accountID,
+				SymbolId:            []int64{symbolID},
+			})
+		}
+	case *openapi.ProtoOASubscribeLiveTrendbarReq:
+		key := subscriptionKey{
+			ctidTraderAccountId: r.GetCtidTraderAccountId(),
+			symbolId:            r.GetSymbolId(),
+			period:              int32(r.GetPeriod()),
+			kind:                kindTrendbar,
+		}
+		s.setSubscription(key, req)
+	case *openapi.ProtoOAUnsubscribeSpotsReq:
+		for _, symbolID := range r.GetSymbolId() {
+			s.deleteSubscription(subscriptionKey{ctidTraderAccountId: r.GetCtidTraderAccountId(), symbolId: symbolID, kind: kindSpot})
+		}
+	case *openapi.ProtoOAUnsubscribeDepthQuotesReq:
+		for _, symbolID := range r.GetSymbolId() {
+			s.deleteSubscription(subscriptionKey{ctidTraderAccountId: r.GetCtidTraderAccountId(), symbolId: symbolID, kind: kindDepth})
+		}
+	case *openapi.ProtoOAUnsubscribeLiveTrendbarReq:
+		s.deleteSubscription(subscriptionKey{
+			ctidTraderAccountId: r.GetCtidTraderAccountId(),
+			symbolId:            r.GetSymbolId(),
+			period:              int32(r.GetPeriod()),
+			kind:                kindTrendbar,
+		})
+	}
+}
+
+func (s *sessionState) setAccountAuth(ctidTraderAccountId int64, req proto.Message) {
+	if _, ok := s.accountAuths[ctidTraderAccountId]; !ok {
+		s.accountAuthOrder = append(s.accountAuthOrder, ctidTraderAccountId)
+	}
+	s.accountAuths[ctidTraderAccountId] = req
+}
+
+func (s *sessionState) setSubscription(key subscriptionKey, req proto.Message) {
+	if _, ok := s.subscriptions[key]; !ok {
+		s.subscriptionOrder = append(s.subscriptionOrder, key)
+	}
+	s.subscriptions[key] = req
+}
+
+func (s *sessionState) deleteSubscription(key subscriptionKey) {
+	if _, ok := s.subscriptions[key]; !ok {
+		return
+	}
+	delete(s.subscriptions, key)
+	for i, k := range s.subscriptionOrder {
+		if k == key {
+			s.subscriptionOrder = append(s.subscriptionOrder[:i], s.subscriptionOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// snapshot returns every recorded account authorization followed by every recorded subscription,
+// in the order each was first requested, so replay restores the session deterministically.
+func (s *sessionState) snapshot() []proto.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]proto.Message, 0, len(s.accountAuthOrder)+len(s.subscriptionOrder))
+	for _, id := range s.accountAuthOrder {
+		entries = append(entries, s.accountAuths[id])
+	}
+	for _, key := range s.subscriptionOrder {
+		entries = append(entries, s.subscriptions[key])
+	}
+	return entries
+}
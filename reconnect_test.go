@@ -0,0 +1,99 @@
+package ctrader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diegobernardes/ctrader/openapi"
+)
+
+func TestReconnectPolicyDelay(t *testing.T) {
+	policy := ReconnectPolicy{InitialDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	for attempt := 0; attempt < 6; attempt++ {
+		delay := policy.delay(attempt)
+		if delay < 0 || delay > policy.MaxDelay {
+			t.Fatalf("attempt %d: delay %s out of [0, %s]", attempt, delay, policy.MaxDelay)
+		}
+	}
+}
+
+func TestSessionStateSnapshotOrder(t *testing.T) {
+	var s sessionState
+
+	accountA := int64(1)
+	accountB := int64(2)
+	symbolA := int64(100)
+	symbolB := int64(200)
+
+	s.record(&openapi.ProtoOAAccountAuthReq{CtidTraderAccountId: &accountB})
+	s.record(&openapi.ProtoOAAccountAuthReq{CtidTraderAccountId: &accountA})
+	s.record(&openapi.ProtoOASubscribeSpotsReq{CtidTraderAccountId: &accountA, SymbolId: []int64{symbolB}})
+	s.record(&openapi.ProtoOASubscribeSpotsReq{CtidTraderAccountId: &accountA, SymbolId: []int64{symbolA}})
+
+	got := s.snapshot()
+	if len(got) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(got))
+	}
+
+	auth1, ok := got[0].(*openapi.ProtoOAAccountAuthReq)
+	if !ok || auth1.GetCtidTraderAccountId() != accountB {
+		t.Fatalf("expected first entry to be the account B auth recorded first, got %#v", got[0])
+	}
+	auth2, ok := got[1].(*openapi.ProtoOAAccountAuthReq)
+	if !ok || auth2.GetCtidTraderAccountId() != accountA {
+		t.Fatalf("expected second entry to be the account A auth recorded second, got %#v", got[1])
+	}
+	sub1, ok := got[2].(*openapi.ProtoOASubscribeSpotsReq)
+	if !ok || sub1.GetSymbolId()[0] != symbolB {
+		t.Fatalf("expected third entry to be the symbol B subscription recorded first, got %#v", got[2])
+	}
+	sub2, ok := got[3].(*openapi.ProtoOASubscribeSpotsReq)
+	if !ok || sub2.GetSymbolId()[0] != symbolA {
+		t.Fatalf("expected fourth entry to be the symbol A subscription recorded second, got %#v", got[3])
+	}
+}
+
+func TestSessionStateMultiSymbolSubscribeReplaysEachSymbolOnce(t *testing.T) {
+	var s sessionState
+
+	account := int64(1)
+	symbolA := int64(100)
+	symbolB := int64(200)
+
+	s.record(&openapi.ProtoOASubscribeSpotsReq{CtidTraderAccountId: &account, SymbolId: []int64{symbolA, symbolB}})
+
+	got := s.snapshot()
+	if len(got) != 2 {
+		t.Fatalf("expected one replay entry per symbol, got %d: %#v", len(got), got)
+	}
+
+	seen := make(map[int64]bool)
+	for _, entry := range got {
+		sub, ok := entry.(*openapi.ProtoOASubscribeSpotsReq)
+		if !ok {
+			t.Fatalf("expected a ProtoOASubscribeSpotsReq, got %#v", entry)
+		}
+		if len(sub.GetSymbolId()) != 1 {
+			t.Fatalf("expected each replay entry to carry exactly one symbol, got %v", sub.GetSymbolId())
+		}
+		seen[sub.GetSymbolId()[0]] = true
+	}
+	if !seen[symbolA] || !seen[symbolB] {
+		t.Fatalf("expected both symbols to be replayed exactly once, got %#v", got)
+	}
+}
+
+func TestSessionStateUnsubscribeRemovesFromOrder(t *testing.T) {
+	var s sessionState
+
+	account := int64(1)
+	symbol := int64(100)
+
+	s.record(&openapi.ProtoOASubscribeSpotsReq{CtidTraderAccountId: &account, SymbolId: []int64{symbol}})
+	s.record(&openapi.ProtoOAUnsubscribeSpotsReq{CtidTraderAccountId: &account, SymbolId: []int64{symbol}})
+
+	if got := s.snapshot(); len(got) != 0 {
+		t.Fatalf("expected no entries after unsubscribe, got %d", len(got))
+	}
+}
@@ -0,0 +1,382 @@
+package ctrader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/diegobernardes/ctrader/openapi"
+)
+
+// subscriptionKind discriminates the event streams a subscriptionKey can identify, since spot,
+// depth and trendbar subscriptions can all share the same ctidTraderAccountId/symbolId pair.
+type subscriptionKind int
+
+const (
+	kindSpot subscriptionKind = iota
+	kindDepth
+	kindTrendbar
+	kindExecution
+)
+
+// defaultSubscriptionBuffer is the channel buffer size used when Client.SubscriptionBufferSize
+// is left at zero.
+const defaultSubscriptionBuffer = 64
+
+// subscriber is one consumer registered against a subscriptionKey. ch carries proto.Message; the
+// exported Subscribe helpers wrap it in a type-asserting forwarder so callers see a typed channel.
+//
+// send and closeChan share mu so a cancel() racing the transport read loop's dispatch can never
+// close ch while dispatch is sending to it: whichever of the two gets there first either delivers
+// the message or marks the subscriber closed, and the other sees a consistent state instead of
+// panicking on a send to a closed channel.
+type subscriber struct {
+	ch chan proto.Message
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// send delivers msg to s, dropping the oldest pending message if ch is full so a slow consumer
+// never stalls the caller. It is a no-op once closeChan has run.
+func (s *subscriber) send(msg proto.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- msg:
+	default:
+		select {
+		case <-s.ch:
+		default:
+		}
+		select {
+		case s.ch <- msg:
+		default:
+		}
+	}
+}
+
+// closeChan closes ch, synchronized with send so a concurrent dispatch can never send on a
+// closed channel. Safe to call more than once.
+func (s *subscriber) closeChan() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.ch)
+}
+
+// subscriptionEngine fans inbound events out to typed consumer channels and reference-counts the
+// underlying wire subscriptions, so that N overlapping Subscribe calls for the same
+// account/symbol/period result in exactly one SUBSCRIBE_*_REQ and one UNSUBSCRIBE_*_REQ.
+type subscriptionEngine struct {
+	mu    sync.Mutex
+	refs  map[subscriptionKey]int
+	chans map[subscriptionKey][]*subscriber
+}
+
+func (e *subscriptionEngine) init() {
+	if e.refs == nil {
+		e.refs = make(map[subscriptionKey]int)
+		e.chans = make(map[subscriptionKey][]*subscriber)
+	}
+}
+
+// acquire increments the reference count for key and reports whether this was the first
+// reference, meaning the caller must issue the wire subscribe request.
+func (e *subscriptionEngine) acquire(key subscriptionKey) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.init()
+	e.refs[key]++
+	return e.refs[key] == 1
+}
+
+// release decrements the reference count for key and reports whether it reached zero, meaning
+// the caller must issue the wire unsubscribe request.
+func (e *subscriptionEngine) release(key subscriptionKey) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.refs[key]--
+	if e.refs[key] <= 0 {
+		delete(e.refs, key)
+		return true
+	}
+	return false
+}
+
+// register adds sub as a consumer of key.
+func (e *subscriptionEngine) register(key subscriptionKey, sub *subscriber) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.init()
+	e.chans[key] = append(e.chans[key], sub)
+}
+
+// unregister removes sub from key's consumers.
+func (e *subscriptionEngine) unregister(key subscriptionKey, sub *subscriber) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	subs := e.chans[key]
+	for i, s := range subs {
+		if s == sub {
+			e.chans[key] = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+}
+
+// dispatch delivers msg to every consumer registered for key. Channels are buffered and
+// non-blocking: a full channel has its oldest pending message dropped to make room, so a slow
+// consumer cannot stall the caller (the transport read loop). It reports whether key had any
+// consumer at all.
+func (e *subscriptionEngine) dispatch(key subscriptionKey, msg proto.Message) bool {
+	e.mu.Lock()
+	subs := append([]*subscriber(nil), e.chans[key]...)
+	e.mu.Unlock()
+	if len(subs) == 0 {
+		return false
+	}
+	for _, sub := range subs {
+		sub.send(msg)
+	}
+	return true
+}
+
+// route inspects an inbound event and fans it out to any typed subscribers registered for it. It
+// reports whether the event matched a known, subscribed stream; handlerMessage falls back to
+// Client.HandlerEvent when it does not, so unrecognised or unsubscribed events are never lost.
+func (e *subscriptionEngine) route(message proto.Message) bool {
+	switch m := message.(type) {
+	case *openapi.ProtoOASpotEvent:
+		spot := e.dispatch(subscriptionKey{
+			ctidTraderAccountId: m.GetCtidTraderAccountId(),
+			symbolId:            m.GetSymbolId(),
+			kind:                kindSpot,
+		}, message)
+		trendbar := false
+		for _, tb := range m.GetTrendbar() {
+			key := subscriptionKey{
+				ctidTraderAccountId: m.GetCtidTraderAccountId(),
+				symbolId:            m.GetSymbolId(),
+				period:              int32(tb.GetPeriod()),
+				kind:                kindTrendbar,
+			}
+			if e.dispatch(key, tb) {
+				trendbar = true
+			}
+		}
+		return spot || trendbar
+	case *openapi.ProtoOADepthEvent:
+		return e.dispatch(subscriptionKey{
+			ctidTraderAccountId: m.GetCtidTraderAccountId(),
+			symbolId:            m.GetSymbolId(),
+			kind:                kindDepth,
+		}, message)
+	case *openapi.ProtoOAExecutionEvent:
+		return e.dispatch(subscriptionKey{
+			ctidTraderAccountId: m.GetCtidTraderAccountId(),
+			kind:                kindExecution,
+		}, message)
+	default:
+		return false
+	}
+}
+
+// bufferSize returns Client.SubscriptionBufferSize, or defaultSubscriptionBuffer if unset.
+func (c *Client[T]) bufferSize() int {
+	if c.SubscriptionBufferSize > 0 {
+		return c.SubscriptionBufferSize
+	}
+	return defaultSubscriptionBuffer
+}
+
+// forward type-asserts every message off in against M and relays matches onto the channel it
+// returns, closing it once in closes. Messages that don't assert (which should not happen given
+// how callers build in) are silently dropped rather than panicking a consumer.
+func forward[M proto.Message](in chan proto.Message) <-chan M {
+	out := make(chan M, cap(in))
+	go func() {
+		defer close(out)
+		for msg := range in {
+			if typed, ok := msg.(M); ok {
+				out <- typed
+			}
+		}
+	}()
+	return out
+}
+
+// SubscribeSpots subscribes to bid/ask updates for symbolIDs on ctidTraderAccountId. Calling it
+// again for overlapping symbols reuses the existing wire subscription for those symbols and only
+// sends PROTO_OA_SUBSCRIBE_SPOTS_REQ for the ones not already subscribed; the returned cancel
+// func only sends PROTO_OA_UNSUBSCRIBE_SPOTS_REQ for symbols no other caller still needs.
+func (c *Client[T]) SubscribeSpots(
+	ctx context.Context, ctidTraderAccountId int64, symbolIDs ...int64,
+) (<-chan *openapi.ProtoOASpotEvent, func() error, error) {
+	ch, cancel, err := subscribeSymbols(c, ctx, kindSpot, ctidTraderAccountId, symbolIDs,
+		func(ids []int64) proto.Message {
+			return &openapi.ProtoOASubscribeSpotsReq{CtidTraderAccountId: &ctidTraderAccountId, SymbolId: ids}
+		},
+		func(ids []int64) proto.Message {
+			return &openapi.ProtoOAUnsubscribeSpotsReq{CtidTraderAccountId: &ctidTraderAccountId, SymbolId: ids}
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return forward[*openapi.ProtoOASpotEvent](ch), cancel, nil
+}
+
+// SubscribeDepth subscribes to market depth updates for symbolIDs on ctidTraderAccountId, with
+// the same incremental subscribe/unsubscribe and reference counting as SubscribeSpots.
+func (c *Client[T]) SubscribeDepth(
+	ctx context.Context, ctidTraderAccountId int64, symbolIDs ...int64,
+) (<-chan *openapi.ProtoOADepthEvent, func() error, error) {
+	ch, cancel, err := subscribeSymbols(c, ctx, kindDepth, ctidTraderAccountId, symbolIDs,
+		func(ids []int64) proto.Message {
+			return &openapi.ProtoOASubscribeDepthQuotesReq{CtidTraderAccountId: &ctidTraderAccountId, SymbolId: ids}
+		},
+		func(ids []int64) proto.Message {
+			return &openapi.ProtoOAUnsubscribeDepthQuotesReq{CtidTraderAccountId: &ctidTraderAccountId, SymbolId: ids}
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return forward[*openapi.ProtoOADepthEvent](ch), cancel, nil
+}
+
+// SubscribeLiveTrendbar subscribes to live trendbar updates for a single symbol/period pair on
+// ctidTraderAccountId. Updates arrive embedded in PROTO_OA_SPOT_EVENT and are unpacked per
+// trendbar before being delivered here, so this channel only ever carries bars for period.
+func (c *Client[T]) SubscribeLiveTrendbar(
+	ctx context.Context, ctidTraderAccountId, symbolID int64, period openapi.ProtoOATrendbarPeriod,
+) (<-chan *openapi.ProtoOATrendbar, func() error, error) {
+	key := subscriptionKey{ctidTraderAccountId: ctidTraderAccountId, symbolId: symbolID, period: int32(period), kind: kindTrendbar}
+
+	sub := &subscriber{ch: make(chan proto.Message, c.bufferSize())}
+	c.subs.register(key, sub)
+
+	if c.subs.acquire(key) {
+		req := &openapi.ProtoOASubscribeLiveTrendbarReq{
+			CtidTraderAccountId: &ctidTraderAccountId,
+			SymbolId:            &symbolID,
+			Period:              &period,
+		}
+		if _, err := c.send(ctx, req, true); err != nil {
+			c.subs.release(key)
+			c.subs.unregister(key, sub)
+			return nil, nil, fmt.Errorf("failed to subscribe to live trendbars: %w", err)
+		}
+	}
+
+	cancel := func() error {
+		c.subs.unregister(key, sub)
+		sub.closeChan()
+		if !c.subs.release(key) {
+			return nil
+		}
+		req := &openapi.ProtoOAUnsubscribeLiveTrendbarReq{
+			CtidTraderAccountId: &ctidTraderAccountId,
+			SymbolId:            &symbolID,
+			Period:              &period,
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err := c.send(ctx, req, true); err != nil {
+			return fmt.Errorf("failed to unsubscribe from live trendbars: %w", err)
+		}
+		return nil
+	}
+	return forward[*openapi.ProtoOATrendbar](sub.ch), cancel, nil
+}
+
+// SubscribeExecution streams every PROTO_OA_EXECUTION_EVENT for ctidTraderAccountId. Unlike the
+// other Subscribe helpers this needs no wire request: the server sends execution events for an
+// authorized account unconditionally, so this only registers a local consumer.
+func (c *Client[T]) SubscribeExecution(ctidTraderAccountId int64) (<-chan *openapi.ProtoOAExecutionEvent, func() error) {
+	key := subscriptionKey{ctidTraderAccountId: ctidTraderAccountId, kind: kindExecution}
+	sub := &subscriber{ch: make(chan proto.Message, c.bufferSize())}
+	c.subs.register(key, sub)
+
+	cancel := func() error {
+		c.subs.unregister(key, sub)
+		sub.closeChan()
+		return nil
+	}
+	return forward[*openapi.ProtoOAExecutionEvent](sub.ch), cancel
+}
+
+// subscribeSymbols implements the shared incremental-subscribe / ref-counted-unsubscribe pattern
+// used by SubscribeSpots and SubscribeDepth: a single call can cover several symbolIDs, only the
+// ones not already subscribed trigger buildSubscribeReq, and cancelling only sends
+// buildUnsubscribeReq for the ones no other caller still references.
+func subscribeSymbols[T clientTransport](
+	c *Client[T], ctx context.Context, kind subscriptionKind, ctidTraderAccountId int64, symbolIDs []int64,
+	buildSubscribeReq, buildUnsubscribeReq func(symbolIDs []int64) proto.Message,
+) (chan proto.Message, func() error, error) {
+	if len(symbolIDs) == 0 {
+		return nil, nil, fmt.Errorf("ctrader: at least one symbol ID is required")
+	}
+
+	keys := make([]subscriptionKey, len(symbolIDs))
+	for i, symbolID := range symbolIDs {
+		keys[i] = subscriptionKey{ctidTraderAccountId: ctidTraderAccountId, symbolId: symbolID, kind: kind}
+	}
+
+	sub := &subscriber{ch: make(chan proto.Message, c.bufferSize())}
+	var newSymbolIDs []int64
+	var newKeys []subscriptionKey
+	for i, key := range keys {
+		c.subs.register(key, sub)
+		if c.subs.acquire(key) {
+			newSymbolIDs = append(newSymbolIDs, symbolIDs[i])
+			newKeys = append(newKeys, key)
+		}
+	}
+
+	if len(newSymbolIDs) > 0 {
+		if _, err := c.send(ctx, buildSubscribeReq(newSymbolIDs), true); err != nil {
+			// Unregister sub from every key it joined, but only release the keys this call
+			// actually acquired: releasing a key someone else already held would drop their
+			// refcount to zero under them and desync the wire state.
+			for _, key := range keys {
+				c.subs.unregister(key, sub)
+			}
+			for _, key := range newKeys {
+				c.subs.release(key)
+			}
+			return nil, nil, fmt.Errorf("failed to subscribe: %w", err)
+		}
+	}
+
+	cancel := func() error {
+		sub.closeChan()
+		var staleSymbolIDs []int64
+		for i, key := range keys {
+			c.subs.unregister(key, sub)
+			if c.subs.release(key) {
+				staleSymbolIDs = append(staleSymbolIDs, symbolIDs[i])
+			}
+		}
+		if len(staleSymbolIDs) == 0 {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err := c.send(ctx, buildUnsubscribeReq(staleSymbolIDs), true); err != nil {
+			return fmt.Errorf("failed to unsubscribe: %w", err)
+		}
+		return nil
+	}
+	return sub.ch, cancel, nil
+}
@@ -0,0 +1,87 @@
+package ctrader
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSubscriptionEngineAcquireReleaseRefcount(t *testing.T) {
+	var e subscriptionEngine
+	key := subscriptionKey{ctidTraderAccountId: 1, symbolId: 100, kind: kindSpot}
+
+	if !e.acquire(key) {
+		t.Fatalf("first acquire should report the wire subscribe is needed")
+	}
+	if e.acquire(key) {
+		t.Fatalf("second acquire should reuse the existing subscription")
+	}
+	if e.release(key) {
+		t.Fatalf("release with one remaining reference should not report the wire unsubscribe is needed")
+	}
+	if !e.release(key) {
+		t.Fatalf("release of the last reference should report the wire unsubscribe is needed")
+	}
+}
+
+func TestSubscriptionEngineReleaseDoesNotUnderflowForeignConsumer(t *testing.T) {
+	var e subscriptionEngine
+	key := subscriptionKey{ctidTraderAccountId: 1, symbolId: 100, kind: kindSpot}
+
+	e.acquire(key) // consumer A holds the subscription
+	if e.acquire(key) {
+		t.Fatalf("consumer B's acquire should not report a fresh wire subscribe is needed")
+	}
+
+	// Releasing B's reference must leave A's reference, and the wire subscription, in place;
+	// only the release of the last reference reports that the unsubscribe is needed.
+	if e.release(key) {
+		t.Fatalf("release of B's reference should leave A's reference intact")
+	}
+	if !e.release(key) {
+		t.Fatalf("release of A's final reference should report the wire unsubscribe is needed")
+	}
+}
+
+func TestSubscriptionEngineUnregisterRemovesConsumer(t *testing.T) {
+	var e subscriptionEngine
+	key := subscriptionKey{ctidTraderAccountId: 1, symbolId: 100, kind: kindSpot}
+
+	subA := &subscriber{ch: make(chan proto.Message, 1)}
+	subB := &subscriber{ch: make(chan proto.Message, 1)}
+	e.register(key, subA)
+	e.register(key, subB)
+
+	e.unregister(key, subA)
+	if !e.dispatch(key, &wireMessageStub{}) {
+		t.Fatalf("dispatch should still report a consumer for subB")
+	}
+	select {
+	case <-subB.ch:
+	default:
+		t.Fatalf("subB should have received the dispatched message")
+	}
+	select {
+	case <-subA.ch:
+		t.Fatalf("subA was unregistered and should not receive dispatched messages")
+	default:
+	}
+}
+
+func TestSubscriberCloseChanIsSafeConcurrentWithSend(t *testing.T) {
+	sub := &subscriber{ch: make(chan proto.Message, 1)}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			sub.send(&wireMessageStub{})
+		}
+	}()
+	sub.closeChan()
+	<-done
+}
+
+// wireMessageStub is a minimal proto.Message used only to exercise dispatch/send without pulling
+// in a real openapi event type.
+type wireMessageStub struct{ proto.Message }
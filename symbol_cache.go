@@ -0,0 +1,244 @@
+package ctrader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/diegobernardes/ctrader/openapi"
+)
+
+// batchWindow is how long Get waits for other concurrent misses on the same account before
+// issuing the PROTO_OA_SYMBOL_BY_ID_REQ, so a strategy hydrating dozens of symbols at startup
+// collapses into a single round trip instead of one per symbol.
+const batchWindow = 5 * time.Millisecond
+
+// SymbolInfo is the subset of PROTO_OA_SYMBOL_BY_ID_RES needed to convert between the relative
+// prices/volumes an application works with and the integer pips/centilots the wire protocol
+// uses.
+type SymbolInfo struct {
+	SymbolID    int64
+	Digits      int32
+	PipPosition int32
+	LotSize     int64
+	MinVolume   int64
+	MaxVolume   int64
+	StepVolume  int64
+}
+
+type symbolKey struct {
+	ctidTraderAccountId int64
+	symbolId            int64
+}
+
+// symbolBatch coalesces concurrent misses for the same account into one SYMBOL_BY_ID_REQ.
+type symbolBatch struct {
+	mu      sync.Mutex
+	ids     map[int64]struct{}
+	done    chan struct{}
+	err     error
+	flushed bool
+}
+
+// SymbolCache lazily loads and caches symbol metadata per (ctidTraderAccountId, symbolId), and
+// offers the price/volume conversions every quote and order needs. It is safe for concurrent
+// use. Client.handlerMessage invalidates the cached entry for a symbol automatically whenever a
+// PROTO_OA_SYMBOL_CHANGED_EVENT arrives for it, once the cache has been created via Symbols().
+type SymbolCache[T clientTransport] struct {
+	client *Client[T]
+
+	mu      sync.Mutex
+	symbols map[symbolKey]*SymbolInfo
+	batches map[int64]*symbolBatch
+}
+
+// NewSymbolCache creates a SymbolCache bound to client. client.handlerMessage invalidates entries
+// on PROTO_OA_SYMBOL_CHANGED_EVENT only in the cache returned by client.Symbols(); a cache created
+// here directly is not invalidated automatically and relies on Invalidate.
+func NewSymbolCache[T clientTransport](client *Client[T]) *SymbolCache[T] {
+	return &SymbolCache[T]{
+		client:  client,
+		symbols: make(map[symbolKey]*SymbolInfo),
+		batches: make(map[int64]*symbolBatch),
+	}
+}
+
+// Symbols returns the lazily created SymbolCache for c, creating it on first use. The cache is
+// stored behind an atomic pointer, not a plain field, because handlerMessage reads it from the
+// transport's read-loop goroutine to invalidate entries on PROTO_OA_SYMBOL_CHANGED_EVENT, which
+// can run concurrently with a caller's first Symbols() call once Start has been invoked.
+func (c *Client[T]) Symbols() *SymbolCache[T] {
+	c.symbolCacheOnce.Do(func() {
+		c.symbolCache.Store(NewSymbolCache(c))
+	})
+	return c.symbolCache.Load()
+}
+
+// Get returns the cached SymbolInfo for symbolID on ctidTraderAccountId, loading it first if
+// needed. Concurrent misses for the same account within batchWindow are folded into a single
+// PROTO_OA_SYMBOL_BY_ID_REQ.
+func (s *SymbolCache[T]) Get(ctx context.Context, ctidTraderAccountId, symbolID int64) (*SymbolInfo, error) {
+	key := symbolKey{ctidTraderAccountId: ctidTraderAccountId, symbolId: symbolID}
+
+	s.mu.Lock()
+	if info, ok := s.symbols[key]; ok {
+		s.mu.Unlock()
+		return info, nil
+	}
+	batch, ok := s.batches[ctidTraderAccountId]
+	if !ok {
+		batch = &symbolBatch{ids: make(map[int64]struct{}), done: make(chan struct{})}
+		s.batches[ctidTraderAccountId] = batch
+		time.AfterFunc(batchWindow, func() { s.flush(ctidTraderAccountId, batch) })
+	}
+	batch.mu.Lock()
+	batch.ids[symbolID] = struct{}{}
+	batch.mu.Unlock()
+	s.mu.Unlock()
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %w", ErrTimeout, ctx.Err())
+	}
+
+	if batch.err != nil {
+		return nil, batch.err
+	}
+	s.mu.Lock()
+	info, ok := s.symbols[key]
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrSymbolNotFound
+	}
+	return info, nil
+}
+
+// flush sends the batched PROTO_OA_SYMBOL_BY_ID_REQ for ctidTraderAccountId and wakes up every
+// Get call waiting on it.
+func (s *SymbolCache[T]) flush(ctidTraderAccountId int64, batch *symbolBatch) {
+	s.mu.Lock()
+	if s.batches[ctidTraderAccountId] == batch {
+		delete(s.batches, ctidTraderAccountId)
+	}
+	s.mu.Unlock()
+
+	batch.mu.Lock()
+	symbolIDs := make([]int64, 0, len(batch.ids))
+	for id := range batch.ids {
+		symbolIDs = append(symbolIDs, id)
+	}
+	batch.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req := &openapi.ProtoOASymbolByIdReq{CtidTraderAccountId: &ctidTraderAccountId, SymbolId: symbolIDs}
+	res, err := s.client.send(ctx, req, true)
+	if err != nil {
+		batch.err = fmt.Errorf("failed to load symbols: %w", err)
+		close(batch.done)
+		return
+	}
+
+	symbolRes, ok := res.(*openapi.ProtoOASymbolByIdRes)
+	if !ok {
+		batch.err = fmt.Errorf("ctrader: unexpected response type %T for symbol by ID request", res)
+		close(batch.done)
+		return
+	}
+
+	s.mu.Lock()
+	for _, sym := range symbolRes.GetSymbol() {
+		key := symbolKey{ctidTraderAccountId: ctidTraderAccountId, symbolId: sym.GetSymbolId()}
+		s.symbols[key] = &SymbolInfo{
+			SymbolID:    sym.GetSymbolId(),
+			Digits:      sym.GetDigits(),
+			PipPosition: sym.GetPipPosition(),
+			LotSize:     sym.GetLotSize(),
+			MinVolume:   sym.GetMinVolume(),
+			MaxVolume:   sym.GetMaxVolume(),
+			StepVolume:  sym.GetStepVolume(),
+		}
+	}
+	s.mu.Unlock()
+	close(batch.done)
+}
+
+// Invalidate drops the cached entry for symbolID on ctidTraderAccountId. Client.handlerMessage
+// already calls this on PROTO_OA_SYMBOL_CHANGED_EVENT; exported for callers that need to evict a
+// symbol for some other reason.
+func (s *SymbolCache[T]) Invalidate(ctidTraderAccountId, symbolID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.symbols, symbolKey{ctidTraderAccountId: ctidTraderAccountId, symbolId: symbolID})
+}
+
+// NormalizePrice converts a relative price (e.g. 1.23456) into the integer representation the
+// wire protocol uses, scaled by the symbol's digits.
+func (s *SymbolCache[T]) NormalizePrice(ctx context.Context, ctidTraderAccountId, symbolID int64, price float64) (int64, error) {
+	info, err := s.Get(ctx, ctidTraderAccountId, symbolID)
+	if err != nil {
+		return 0, err
+	}
+	scale := pow10(info.Digits)
+	return int64(price*scale + sign(price)*0.5), nil
+}
+
+// PriceFromRelative is the inverse of NormalizePrice: it converts the integer, pip-scaled price
+// the wire protocol uses back into a relative price such as 1.23456.
+func (s *SymbolCache[T]) PriceFromRelative(ctx context.Context, ctidTraderAccountId, symbolID int64, price int64) (float64, error) {
+	info, err := s.Get(ctx, ctidTraderAccountId, symbolID)
+	if err != nil {
+		return 0, err
+	}
+	return float64(price) / pow10(info.Digits), nil
+}
+
+// NormalizeVolume converts a volume in lots into the integer centilot/unit representation the
+// wire protocol uses, honoring the symbol's lotSize, minVolume, maxVolume and stepVolume.
+func (s *SymbolCache[T]) NormalizeVolume(ctx context.Context, ctidTraderAccountId, symbolID int64, lots float64) (int64, error) {
+	info, err := s.Get(ctx, ctidTraderAccountId, symbolID)
+	if err != nil {
+		return 0, err
+	}
+	volume := int64(lots*float64(info.LotSize) + 0.5)
+	if info.StepVolume > 0 {
+		volume = (volume / info.StepVolume) * info.StepVolume
+	}
+	switch {
+	case volume < info.MinVolume:
+		return 0, fmt.Errorf("ctrader: volume %.2f lots is below the minimum for symbol %d", lots, symbolID)
+	case info.MaxVolume > 0 && volume > info.MaxVolume:
+		return 0, fmt.Errorf("ctrader: volume %.2f lots is above the maximum for symbol %d", lots, symbolID)
+	}
+	return volume, nil
+}
+
+// VolumeToLots is the inverse of NormalizeVolume: it converts the integer volume the wire
+// protocol uses back into lots.
+func (s *SymbolCache[T]) VolumeToLots(ctx context.Context, ctidTraderAccountId, symbolID, volume int64) (float64, error) {
+	info, err := s.Get(ctx, ctidTraderAccountId, symbolID)
+	if err != nil {
+		return 0, err
+	}
+	if info.LotSize == 0 {
+		return 0, fmt.Errorf("ctrader: symbol %d has no lot size", symbolID)
+	}
+	return float64(volume) / float64(info.LotSize), nil
+}
+
+func pow10(n int32) float64 {
+	result := 1.0
+	for i := int32(0); i < n; i++ {
+		result *= 10
+	}
+	return result
+}
+
+func sign(v float64) float64 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
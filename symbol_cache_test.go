@@ -0,0 +1,99 @@
+package ctrader
+
+import (
+	"context"
+	"testing"
+)
+
+// stubTransport is a no-op clientTransport used only to construct a Client for tests that never
+// actually touch the wire, such as SymbolCache tests seeding their cache directly.
+type stubTransport struct{}
+
+func (stubTransport) start(string) error                   { return nil }
+func (stubTransport) stop() error                          { return nil }
+func (stubTransport) send([]byte) error                    { return nil }
+func (stubTransport) setHandler(func([]byte), func(error)) {}
+
+func newTestSymbolCache() *SymbolCache[stubTransport] {
+	client := &Client[stubTransport]{}
+	return NewSymbolCache(client)
+}
+
+func seedSymbol(cache *SymbolCache[stubTransport], ctidTraderAccountId, symbolID int64, info SymbolInfo) {
+	cache.symbols[symbolKey{ctidTraderAccountId: ctidTraderAccountId, symbolId: symbolID}] = &info
+}
+
+func TestNormalizeVolumeStepsAndRounds(t *testing.T) {
+	cache := newTestSymbolCache()
+	seedSymbol(cache, 1, 100, SymbolInfo{
+		SymbolID:   100,
+		LotSize:    100000,
+		MinVolume:  1000,
+		MaxVolume:  1000000,
+		StepVolume: 1000,
+	})
+
+	volume, err := cache.NormalizeVolume(context.Background(), 1, 100, 0.123)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 0.123 * 100000 = 12300, rounded down to a multiple of the 1000 step -> 12000.
+	if volume != 12000 {
+		t.Fatalf("expected volume 12000, got %d", volume)
+	}
+}
+
+func TestNormalizeVolumeRejectsBelowMinimum(t *testing.T) {
+	cache := newTestSymbolCache()
+	seedSymbol(cache, 1, 100, SymbolInfo{
+		SymbolID:  100,
+		LotSize:   100000,
+		MinVolume: 50000,
+	})
+
+	if _, err := cache.NormalizeVolume(context.Background(), 1, 100, 0.01); err == nil {
+		t.Fatalf("expected an error for a volume below the minimum")
+	}
+}
+
+func TestNormalizeVolumeRejectsAboveMaximum(t *testing.T) {
+	cache := newTestSymbolCache()
+	seedSymbol(cache, 1, 100, SymbolInfo{
+		SymbolID:  100,
+		LotSize:   100000,
+		MaxVolume: 100000,
+	})
+
+	if _, err := cache.NormalizeVolume(context.Background(), 1, 100, 10); err == nil {
+		t.Fatalf("expected an error for a volume above the maximum")
+	}
+}
+
+func TestNormalizeVolumeNoMaximumMeansUnbounded(t *testing.T) {
+	cache := newTestSymbolCache()
+	seedSymbol(cache, 1, 100, SymbolInfo{
+		SymbolID:  100,
+		LotSize:   100000,
+		MaxVolume: 0,
+	})
+
+	if _, err := cache.NormalizeVolume(context.Background(), 1, 100, 1000); err != nil {
+		t.Fatalf("expected a zero MaxVolume to mean unbounded, got error: %v", err)
+	}
+}
+
+func TestSymbolCacheInvalidateDropsCacheEntry(t *testing.T) {
+	cache := newTestSymbolCache()
+	key := symbolKey{ctidTraderAccountId: 1, symbolId: 100}
+	seedSymbol(cache, 1, 100, SymbolInfo{SymbolID: 100, LotSize: 100000})
+
+	if _, ok := cache.symbols[key]; !ok {
+		t.Fatalf("test setup: expected the seeded entry to be present before Invalidate")
+	}
+
+	cache.Invalidate(1, 100)
+
+	if _, ok := cache.symbols[key]; ok {
+		t.Fatalf("expected Invalidate to remove the cached entry")
+	}
+}
@@ -0,0 +1,196 @@
+package ctrader
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/diegobernardes/ctrader/openapi"
+)
+
+// PayloadClass groups request payload types that share a rate limit under the cTrader Open API.
+// The documented limits differ per class: roughly 50 messages/sec per session for ClassGeneral,
+// 5 requests/sec per account for ClassHistorical, and a tighter quota for ClassOrder.
+type PayloadClass int
+
+const (
+	ClassGeneral PayloadClass = iota
+	ClassHistorical
+	ClassOrder
+)
+
+// classOf returns the PayloadClass a request belongs to for throttling purposes.
+func classOf(req proto.Message) PayloadClass {
+	switch req.(type) {
+	case *openapi.ProtoOAGetTrendbarsReq, *openapi.ProtoOAGetTickDataReq:
+		return ClassHistorical
+	case *openapi.ProtoOANewOrderReq, *openapi.ProtoOAAmendOrderReq, *openapi.ProtoOACancelOrderReq,
+		*openapi.ProtoOAClosePositionReq, *openapi.ProtoOAAmendPositionSLTPReq:
+		return ClassOrder
+	default:
+		return ClassGeneral
+	}
+}
+
+// accountOf returns the ctidTraderAccountId a request targets, or 0 for requests that are not
+// scoped to a trading account (e.g. application authorization).
+func accountOf(req proto.Message) int64 {
+	type hasAccountID interface{ GetCtidTraderAccountId() int64 }
+	if r, ok := req.(hasAccountID); ok {
+		return r.GetCtidTraderAccountId()
+	}
+	return 0
+}
+
+// Throttler paces outbound requests so a burst of calls doesn't trip the cTrader Open API's rate
+// limits. Wait blocks until req may be sent, respecting ctx cancellation. OnThrottled is called
+// whenever the server rejects a request of the given class/account with REQUEST_FREQUENCY_EXCEEDED,
+// so the implementation can shrink its effective rate for the cool-off window.
+type Throttler interface {
+	Wait(ctx context.Context, class PayloadClass, ctidTraderAccountId int64) error
+	OnThrottled(class PayloadClass, ctidTraderAccountId int64, coolOff time.Duration)
+}
+
+// classLimits are the default rates assumed for each PayloadClass, per the limits documented by
+// cTrader. ClassGeneral is per session; ClassHistorical and ClassOrder are per account.
+var classLimits = map[PayloadClass]float64{
+	ClassGeneral:    50,
+	ClassHistorical: 5,
+	ClassOrder:      5,
+}
+
+// tokenBucket is a minimal token-bucket limiter in the style of golang.org/x/time/rate: tokens
+// refill continuously at ratePerSec and Wait blocks until one is available or ctx is done.
+type tokenBucket struct {
+	mu         sync.Mutex
+	ratePerSec float64
+	burst      float64
+	tokens     float64
+	last       time.Time
+}
+
+func newTokenBucket(ratePerSec float64) *tokenBucket {
+	return &tokenBucket{ratePerSec: ratePerSec, burst: ratePerSec, tokens: ratePerSec, last: time.Now()}
+}
+
+func (b *tokenBucket) setRate(ratePerSec float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ratePerSec = ratePerSec
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.ratePerSec
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		missing := 1 - b.tokens
+		wait := time.Duration(missing / b.ratePerSec * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// defaultThrottler is the Throttler used by Client when none is configured. It keeps one token
+// bucket per (class, ctidTraderAccountId), except for ClassGeneral, which shares a single
+// session-wide bucket; buckets are created lazily. It halves the effective rate of a
+// bucket for the duration reported in OnThrottled.
+type defaultThrottler struct {
+	mu      sync.Mutex
+	buckets map[PayloadClass]map[int64]*tokenBucket
+}
+
+// NewDefaultThrottler returns the token-bucket Throttler Client falls back to when Throttler is
+// left nil, using the rate limits documented by cTrader for each PayloadClass.
+func NewDefaultThrottler() Throttler {
+	return &defaultThrottler{buckets: make(map[PayloadClass]map[int64]*tokenBucket)}
+}
+
+// sessionThrottleKey is the fixed bucket key used for ClassGeneral, whose ~50 msg/s limit is per
+// connection rather than per account: without this, authorizing N accounts on one session would
+// hand out N independent 50/s buckets and the client could still trip REQUEST_FREQUENCY_EXCEEDED.
+const sessionThrottleKey int64 = 0
+
+func (t *defaultThrottler) bucket(class PayloadClass, accountID int64) *tokenBucket {
+	if class == ClassGeneral {
+		accountID = sessionThrottleKey
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	byAccount, ok := t.buckets[class]
+	if !ok {
+		byAccount = make(map[int64]*tokenBucket)
+		t.buckets[class] = byAccount
+	}
+	b, ok := byAccount[accountID]
+	if !ok {
+		b = newTokenBucket(classLimits[class])
+		byAccount[accountID] = b
+	}
+	return b
+}
+
+func (t *defaultThrottler) Wait(ctx context.Context, class PayloadClass, ctidTraderAccountId int64) error {
+	if err := t.bucket(class, ctidTraderAccountId).wait(ctx); err != nil {
+		return fmt.Errorf("failed to wait for the throttler: %w", err)
+	}
+	return nil
+}
+
+func (t *defaultThrottler) OnThrottled(class PayloadClass, ctidTraderAccountId int64, coolOff time.Duration) {
+	b := t.bucket(class, ctidTraderAccountId)
+	b.mu.Lock()
+	rate := b.ratePerSec
+	b.mu.Unlock()
+	b.setRate(rate / 2)
+	time.AfterFunc(coolOff, func() { b.setRate(classLimits[class]) })
+}
+
+// throttler returns c.Throttler, falling back to a lazily created defaultThrottler.
+func (c *Client[T]) throttler() Throttler {
+	c.throttlerOnce.Do(func() {
+		if c.Throttler == nil {
+			c.Throttler = NewDefaultThrottler()
+		}
+	})
+	return c.Throttler
+}
+
+// retriableAfterPattern extracts a cool-off duration in seconds from a REQUEST_FREQUENCY_EXCEEDED
+// description, e.g. "Requests limit exceeded, retry after 5 seconds".
+var retriableAfterPattern = regexp.MustCompile(`(\d+)\s*second`)
+
+func parseRetriableAfter(description string) time.Duration {
+	const defaultCoolOff = 5 * time.Second
+	match := retriableAfterPattern.FindStringSubmatch(description)
+	if match == nil {
+		return defaultCoolOff
+	}
+	seconds, err := strconv.Atoi(match[1])
+	if err != nil {
+		return defaultCoolOff
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,25 @@
+package ctrader
+
+import "testing"
+
+func TestDefaultThrottlerClassGeneralIsSessionScoped(t *testing.T) {
+	th := NewDefaultThrottler().(*defaultThrottler)
+
+	accountA := th.bucket(ClassGeneral, 1)
+	accountB := th.bucket(ClassGeneral, 2)
+	if accountA != accountB {
+		t.Fatalf("expected ClassGeneral to share one bucket across accounts, got distinct buckets")
+	}
+}
+
+func TestDefaultThrottlerClassHistoricalAndOrderArePerAccount(t *testing.T) {
+	th := NewDefaultThrottler().(*defaultThrottler)
+
+	for _, class := range []PayloadClass{ClassHistorical, ClassOrder} {
+		accountA := th.bucket(class, 1)
+		accountB := th.bucket(class, 2)
+		if accountA == accountB {
+			t.Fatalf("expected class %v to have a distinct bucket per account", class)
+		}
+	}
+}